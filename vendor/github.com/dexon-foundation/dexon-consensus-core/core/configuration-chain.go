@@ -18,6 +18,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"github.com/dexon-foundation/dexon-consensus-core/common"
 	"github.com/dexon-foundation/dexon-consensus-core/core/crypto"
 	"github.com/dexon-foundation/dexon-consensus-core/core/types"
+	typesDKG "github.com/dexon-foundation/dexon-consensus-core/core/types/dkg"
 )
 
 // Errors for configuration chain..
@@ -35,8 +37,24 @@ var (
 		"tsig is already running")
 	ErrDKGNotReady = fmt.Errorf(
 		"DKG is not ready")
+	ErrTSigTimeout = fmt.Errorf(
+		"tsig is timeout")
 )
 
+// pendingPsigGCInterval is how often the pendingPsig sweeper goroutine looks
+// for entries older than their TTL.
+const pendingPsigGCInterval = 1 * time.Minute
+
+// defaultPendingPsigTTL bounds how long a partial signature may sit in
+// pendingPsig before its corresponding TSIG round is initiated. Psigs older
+// than this are assumed to belong to a round that will never run and are
+// evicted.
+const defaultPendingPsigTTL = 10 * time.Minute
+
+// defaultInitBlockTSigTimeout bounds how long a node waits for the initial
+// configuration block's TSIG to gather enough partial signatures.
+const defaultInitBlockTSigTimeout = 30 * time.Second
+
 type configurationChain struct {
 	ID          types.NodeID
 	recv        dkgReceiver
@@ -45,43 +63,137 @@ type configurationChain struct {
 	logger      common.Logger
 	dkgLock     sync.RWMutex
 	dkgSigner   map[uint64]*dkgShareSecret
-	gpk         map[uint64]*DKGGroupPublicKey
+	gpk         map[uint64]*typesDKG.GroupPublicKey
 	dkgResult   sync.RWMutex
 	tsig        map[common.Hash]*tsigProtocol
 	tsigTouched map[common.Hash]struct{}
 	tsigReady   *sync.Cond
-	// TODO(jimmy-dexon): add timeout to pending psig.
 	pendingPsig map[common.Hash][]*types.DKGPartialSignature
-	prevHash    common.Hash
+	// pendingPsigTouchedTime records when each pendingPsig entry was first
+	// created, so the sweeper goroutine can evict psigs whose TSIG round is
+	// never initiated instead of leaking them forever.
+	pendingPsigTouchedTime map[common.Hash]time.Time
+	pendingPsigTTL         time.Duration
+	prevHash               common.Hash
+	store                  DKGPersistentStore
 }
 
 func newConfigurationChain(
 	ID types.NodeID,
 	recv dkgReceiver,
 	gov Governance,
+	store DKGPersistentStore,
 	logger common.Logger) *configurationChain {
-	return &configurationChain{
-		ID:          ID,
-		recv:        recv,
-		gov:         gov,
-		logger:      logger,
-		dkgSigner:   make(map[uint64]*dkgShareSecret),
-		gpk:         make(map[uint64]*DKGGroupPublicKey),
-		tsig:        make(map[common.Hash]*tsigProtocol),
-		tsigTouched: make(map[common.Hash]struct{}),
-		tsigReady:   sync.NewCond(&sync.Mutex{}),
-		pendingPsig: make(map[common.Hash][]*types.DKGPartialSignature),
+	return newConfigurationChainWithPsigTTL(
+		ID, recv, gov, store, logger, defaultPendingPsigTTL)
+}
+
+// newConfigurationChainWithPsigTTL is newConfigurationChain with an
+// explicit, configurable pendingPsig TTL, split out so callers that need a
+// non-default TTL aren't left reaching into the unexported pendingPsigTTL
+// field after construction.
+func newConfigurationChainWithPsigTTL(
+	ID types.NodeID,
+	recv dkgReceiver,
+	gov Governance,
+	store DKGPersistentStore,
+	logger common.Logger,
+	pendingPsigTTL time.Duration) *configurationChain {
+	cc := &configurationChain{
+		ID:                     ID,
+		recv:                   recv,
+		gov:                    gov,
+		logger:                 logger,
+		dkgSigner:              make(map[uint64]*dkgShareSecret),
+		gpk:                    make(map[uint64]*typesDKG.GroupPublicKey),
+		tsig:                   make(map[common.Hash]*tsigProtocol),
+		tsigTouched:            make(map[common.Hash]struct{}),
+		tsigReady:              sync.NewCond(&sync.Mutex{}),
+		pendingPsig:            make(map[common.Hash][]*types.DKGPartialSignature),
+		pendingPsigTouchedTime: make(map[common.Hash]time.Time),
+		pendingPsigTTL:         pendingPsigTTL,
+		store:                  store,
+	}
+	go cc.sweepPendingPsig()
+	return cc
+}
+
+// sweepPendingPsig periodically evicts pendingPsig entries older than
+// pendingPsigTTL, preventing unbounded memory growth from psigs whose
+// corresponding TSIG round is never initiated.
+func (cc *configurationChain) sweepPendingPsig() {
+	ticker := time.NewTicker(pendingPsigGCInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		cc.evictStalePendingPsig(time.Now())
+	}
+}
+
+// evictStalePendingPsig deletes every pendingPsig entry whose
+// pendingPsigTouchedTime is older than pendingPsigTTL as of now. Split out of
+// sweepPendingPsig's ticker loop so the eviction rule can be exercised
+// directly, on a synthetic clock, instead of waiting out a real TTL.
+func (cc *configurationChain) evictStalePendingPsig(now time.Time) {
+	cc.tsigReady.L.Lock()
+	defer cc.tsigReady.L.Unlock()
+	for hash, touchedTime := range cc.pendingPsigTouchedTime {
+		if now.Sub(touchedTime) < cc.pendingPsigTTL {
+			continue
+		}
+		delete(cc.pendingPsig, hash)
+		delete(cc.pendingPsigTouchedTime, hash)
+		cc.logger.Debug("Evicted stale pendingPsig", "hash", hash)
 	}
 }
 
-func (cc *configurationChain) registerDKG(round uint64, threshold int) {
+// registerDKG starts round's dkgProtocol fresh, always at dkgPhaseInit.
+//
+// A dkgProtocol's secret polynomial and the private shares it has received
+// from peers exist only in RAM: dkgProtocolSnapshot deliberately doesn't
+// carry them, because there is nothing on disk that could be decoded back
+// into them. So if this node crashed mid-round, jumping cc.dkg.phase ahead
+// to whatever was last persisted would make runDKG skip
+// processMasterPublicKeys/proposeNackComplaints/processNackComplaints/
+// enforceNackComplaints/proposeFinalize for phases that never actually ran
+// against *this* dkgProtocol instance, then hand recoverShareSecret a
+// polynomial nobody else has seen and shares nobody actually sent.
+//
+// Instead, a restart always re-runs every phase of runDKG from dkgPhaseInit.
+// That's safe because each phase re-derives its state from Governance's
+// already-converged public record (DKGMasterPublicKeys/DKGComplaints) rather
+// than from anything this node kept in memory, and the corresponding
+// propose* calls are safe to repeat (peers already rebroadcast private
+// shares independently of phase, per the comments in runDKG). Any
+// previously-persisted snapshot is surfaced only as a log line, not acted
+// on.
+func (cc *configurationChain) registerDKG(round uint64, cfg *types.Config) {
 	cc.dkgLock.Lock()
 	defer cc.dkgLock.Unlock()
 	cc.dkg = newDKGProtocol(
 		cc.ID,
 		cc.recv,
 		round,
-		threshold)
+		typesDKG.GetDKGThreshold(cfg))
+	if snapshot, exist := cc.store.GetDKGProtocol(round); exist {
+		cc.logger.Info(
+			"Re-running DKG round from Governance state after a restart",
+			"nodeID", cc.ID, "round", round, "phaseBeforeRestart", snapshot.Phase)
+	}
+	cc.persistDKGProtocol()
+}
+
+// persistDKGProtocol snapshots the in-flight dkgProtocol's round, threshold
+// and phase into cc.store. After a restart registerDKG only logs that
+// snapshot; it does not resume from it, so an interrupted round is re-run
+// from dkgPhaseInit.
+func (cc *configurationChain) persistDKGProtocol() {
+	if cc.dkg == nil {
+		return
+	}
+	if err := cc.store.PutDKGProtocol(cc.dkg); err != nil {
+		cc.logger.Error("failed to persist dkgProtocol",
+			"nodeID", cc.ID, "error", err)
+	}
 }
 
 func (cc *configurationChain) runDKG(round uint64) error {
@@ -93,8 +205,16 @@ func (cc *configurationChain) runDKG(round uint64) error {
 	if func() bool {
 		cc.dkgResult.RLock()
 		defer cc.dkgResult.RUnlock()
-		_, exist := cc.gpk[round]
-		return exist
+		if _, exist := cc.gpk[round]; exist {
+			return true
+		}
+		if signer, gpk, prevHash, exist := cc.store.GetDKGResult(round); exist {
+			cc.dkgSigner[round] = signer
+			cc.gpk[round] = gpk
+			cc.prevHash = prevHash
+			return true
+		}
+		return false
 	}() {
 		return nil
 	}
@@ -104,21 +224,45 @@ func (cc *configurationChain) runDKG(round uint64) error {
 	<-ticker.Tick()
 	cc.dkgLock.Lock()
 	// Phase 2(T = 0): Exchange DKG secret key share.
-	cc.logger.Debug("Calling Governance.DKGMasterPublicKeys", "round", round)
-	cc.dkg.processMasterPublicKeys(cc.gov.DKGMasterPublicKeys(round))
+	if cc.dkg.phase < dkgPhaseMasterPublicKeys {
+		cc.logger.Debug("Calling Governance.DKGMasterPublicKeys", "round", round)
+		cc.dkg.processMasterPublicKeys(cc.gov.DKGMasterPublicKeys(round))
+		cc.dkg.phase = dkgPhaseMasterPublicKeys
+		cc.persistDKGProtocol()
+	}
+	// Phase 2.5(T = 0): Wait until governance reports enough DKGMPKReady
+	// messages (IsDKGMPKReady) before proposing complaints. Without this
+	// barrier, a node whose MPK is merely slow to propagate looks
+	// indistinguishable from one that never published it, which triggers
+	// spurious complaints.
+	cc.dkg.proposeMPKReady()
+	cc.logger.Debug("Calling Governance.IsDKGMPKReady", "round", round)
+	for !cc.gov.IsDKGMPKReady(round) {
+		cc.logger.Info("DKG MPK is not ready yet. Try again later...",
+			"nodeID", cc.ID)
+		time.Sleep(500 * time.Millisecond)
+	}
 	// Phase 3(T = 0~λ): Propose complaint.
 	// Propose complaint is done in `processMasterPublicKeys`.
 	cc.dkgLock.Unlock()
 	<-ticker.Tick()
 	cc.dkgLock.Lock()
 	// Phase 4(T = λ): Propose nack complaints.
-	cc.dkg.proposeNackComplaints()
+	if cc.dkg.phase < dkgPhaseNackComplaints {
+		cc.dkg.proposeNackComplaints()
+		cc.dkg.phase = dkgPhaseNackComplaints
+		cc.persistDKGProtocol()
+	}
 	cc.dkgLock.Unlock()
 	<-ticker.Tick()
 	cc.dkgLock.Lock()
 	// Phase 5(T = 2λ): Propose Anti nack complaint.
-	cc.logger.Debug("Calling Governance.DKGComplaints", "round", round)
-	cc.dkg.processNackComplaints(cc.gov.DKGComplaints(round))
+	if cc.dkg.phase < dkgPhaseAntiNackComplaints {
+		cc.logger.Debug("Calling Governance.DKGComplaints", "round", round)
+		cc.dkg.processNackComplaints(cc.gov.DKGComplaints(round))
+		cc.dkg.phase = dkgPhaseAntiNackComplaints
+		cc.persistDKGProtocol()
+	}
 	cc.dkgLock.Unlock()
 	<-ticker.Tick()
 	cc.dkgLock.Lock()
@@ -128,14 +272,22 @@ func (cc *configurationChain) runDKG(round uint64) error {
 	<-ticker.Tick()
 	cc.dkgLock.Lock()
 	// Phase 7(T = 4λ): Enforce complaints and nack complaints.
-	cc.logger.Debug("Calling Governance.DKGComplaints", "round", round)
-	cc.dkg.enforceNackComplaints(cc.gov.DKGComplaints(round))
+	if cc.dkg.phase < dkgPhaseEnforceComplaints {
+		cc.logger.Debug("Calling Governance.DKGComplaints", "round", round)
+		cc.dkg.enforceNackComplaints(cc.gov.DKGComplaints(round))
+		cc.dkg.phase = dkgPhaseEnforceComplaints
+		cc.persistDKGProtocol()
+	}
 	// Enforce complaint is done in `processPrivateShare`.
 	// Phase 8(T = 5λ): DKG finalize.
 	cc.dkgLock.Unlock()
 	<-ticker.Tick()
 	cc.dkgLock.Lock()
-	cc.dkg.proposeFinalize()
+	if cc.dkg.phase < dkgPhaseFinalize {
+		cc.dkg.proposeFinalize()
+		cc.dkg.phase = dkgPhaseFinalize
+		cc.persistDKGProtocol()
+	}
 	// Phase 9(T = 6λ): DKG is ready.
 	cc.dkgLock.Unlock()
 	<-ticker.Tick()
@@ -150,30 +302,34 @@ func (cc *configurationChain) runDKG(round uint64) error {
 	}
 	cc.logger.Debug("Calling Governance.DKGMasterPublicKeys", "round", round)
 	cc.logger.Debug("Calling Governance.DKGComplaints", "round", round)
-	gpk, err := NewDKGGroupPublicKey(round,
+	gpk, err := typesDKG.NewGroupPublicKey(round,
 		cc.gov.DKGMasterPublicKeys(round),
 		cc.gov.DKGComplaints(round),
 		cc.dkg.threshold)
 	if err != nil {
 		return err
 	}
-	signer, err := cc.dkg.recoverShareSecret(gpk.qualifyIDs)
+	signer, err := cc.dkg.recoverShareSecret(gpk.QualifyIDs)
 	if err != nil {
 		return err
 	}
 	qualifies := ""
-	for nID := range gpk.qualifyNodeIDs {
+	for nID := range gpk.QualifyNodeIDs {
 		qualifies += fmt.Sprintf("%s ", nID.String()[:6])
 	}
 	cc.logger.Info("Qualify Nodes",
 		"nodeID", cc.ID,
 		"round", round,
-		"count", len(gpk.qualifyIDs),
+		"count", len(gpk.QualifyIDs),
 		"qualifies", qualifies)
 	cc.dkgResult.Lock()
 	defer cc.dkgResult.Unlock()
 	cc.dkgSigner[round] = signer
 	cc.gpk[round] = gpk
+	if err := cc.store.PutDKGResult(round, signer, gpk, cc.prevHash); err != nil {
+		cc.logger.Error("failed to persist DKG result",
+			"nodeID", cc.ID, "round", round, "error", err)
+	}
 	return nil
 }
 
@@ -192,7 +348,7 @@ func (cc *configurationChain) preparePartialSignature(
 		ProposerID:       cc.ID,
 		Round:            round,
 		Hash:             hash,
-		PartialSignature: signer.sign(hash),
+		PartialSignature: signer.Sign(hash),
 	}, nil
 }
 
@@ -204,10 +360,29 @@ func (cc *configurationChain) touchTSigHash(hash common.Hash) (first bool) {
 	return !exist
 }
 
+// wakeCondOnDone broadcasts on cond once ctx is done (timeout or explicit
+// cancellation), so a goroutine blocked in cond.Wait() isn't left hanging
+// forever waiting on something that will never arrive. Call the returned
+// stop func once the caller no longer needs the watcher.
+func wakeCondOnDone(ctx context.Context, cond *sync.Cond) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			cond.L.Lock()
+			cond.Broadcast()
+			cond.L.Unlock()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
 func (cc *configurationChain) runTSig(
-	round uint64, hash common.Hash) (
+	ctx context.Context, round uint64, hash common.Hash,
+	timeout time.Duration) (
 	crypto.Signature, error) {
-	gpk, exist := func() (*DKGGroupPublicKey, bool) {
+	gpk, exist := func() (*typesDKG.GroupPublicKey, bool) {
 		cc.dkgResult.RLock()
 		defer cc.dkgResult.RUnlock()
 		gpk, exist := cc.gpk[round]
@@ -216,6 +391,8 @@ func (cc *configurationChain) runTSig(
 	if !exist {
 		return crypto.Signature{}, ErrDKGNotReady
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 	cc.tsigReady.L.Lock()
 	defer cc.tsigReady.L.Unlock()
 	if _, exist := cc.tsig[hash]; exist {
@@ -224,6 +401,7 @@ func (cc *configurationChain) runTSig(
 	cc.tsig[hash] = newTSigProtocol(gpk, hash)
 	pendingPsig := cc.pendingPsig[hash]
 	delete(cc.pendingPsig, hash)
+	delete(cc.pendingPsigTouchedTime, hash)
 	go func() {
 		for _, psig := range pendingPsig {
 			if err := cc.processPartialSignature(psig); err != nil {
@@ -233,9 +411,18 @@ func (cc *configurationChain) runTSig(
 			}
 		}
 	}()
+	// Wake the Wait() loop below once ctx is done, so a timeout or an
+	// explicit cancelation (e.g. the round advancing past this TSIG) doesn't
+	// block forever on partials that will never arrive.
+	stop := wakeCondOnDone(ctx, cc.tsigReady)
+	defer stop()
 	var signature crypto.Signature
 	var err error
 	for func() bool {
+		if ctx.Err() != nil {
+			err = ErrTSigTimeout
+			return false
+		}
 		signature, err = cc.tsig[hash].signature()
 		return err == ErrNotEnoughtPartialSignatures
 	}() {
@@ -249,9 +436,44 @@ func (cc *configurationChain) runTSig(
 	return signature, nil
 }
 
+// runInitBlockTSig drives the TSIG protocol over a deterministic hash derived
+// from round's configuration, letting every participating node produce a
+// signed initial configuration block before regular block agreement begins,
+// without needing a special-cased genesis block.
+func (cc *configurationChain) runInitBlockTSig(
+	round uint64) (crypto.Signature, error) {
+	hash := cc.initBlockHash(round)
+	sig, err := cc.runTSig(
+		context.Background(), round, hash, defaultInitBlockTSigTimeout)
+	if err != nil {
+		return crypto.Signature{}, err
+	}
+	cc.logger.Info("Init block TSIG",
+		"nodeID", cc.ID,
+		"round", round,
+		"signature", sig)
+	return sig, nil
+}
+
+// initBlockHash deterministically derives the hash signed for round's
+// initial configuration block from round's static genesis inputs: the round
+// number, its configuration, and its CRS. Deriving from cc.gov.DKGMasterPublicKeys
+// would be wrong here, since that list is still being populated as MPKs
+// propagate — two nodes calling this before the set has converged would
+// compute different hashes and never agree on one, exactly the bootstrap
+// problem this function exists to avoid.
+func (cc *configurationChain) initBlockHash(round uint64) common.Hash {
+	cfg := cc.gov.Configuration(round)
+	crs := cc.gov.CRS(round)
+	data := []byte(fmt.Sprintf("DEXON-init-block-%d-%+v", round, cfg))
+	data = append(data, crs[:]...)
+	return crypto.Keccak256Hash(data)
+}
+
 func (cc *configurationChain) runBlockTSig(
-	round uint64, hash common.Hash) (crypto.Signature, error) {
-	sig, err := cc.runTSig(round, hash)
+	ctx context.Context, round uint64, hash common.Hash,
+	timeout time.Duration) (crypto.Signature, error) {
+	sig, err := cc.runTSig(ctx, round, hash, timeout)
 	if err != nil {
 		return crypto.Signature{}, err
 	}
@@ -263,8 +485,9 @@ func (cc *configurationChain) runBlockTSig(
 }
 
 func (cc *configurationChain) runCRSTSig(
-	round uint64, crs common.Hash) ([]byte, error) {
-	sig, err := cc.runTSig(round, crs)
+	ctx context.Context, round uint64, crs common.Hash,
+	timeout time.Duration) ([]byte, error) {
+	sig, err := cc.runTSig(ctx, round, crs, timeout)
 	cc.logger.Info("CRS",
 		"nodeID", cc.ID,
 		"round", round+1,
@@ -282,6 +505,27 @@ func (cc *configurationChain) processPrivateShare(
 	return cc.dkg.processPrivateShare(prvShare)
 }
 
+// processMPKReady verifies an incoming DKGMPKReady message and, once valid,
+// records it with governance so IsDKGMPKReady can threshold the ready count
+// the same way it already thresholds IsDKGFinal. Without this, runDKG's MPK
+// barrier would wait on a tally that nothing ever feeds.
+func (cc *configurationChain) processMPKReady(
+	ready *types.DKGMPKReady) error {
+	ok, err := verifyDKGMPKReadySignature(ready)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrIncorrectMPKReadySignature
+	}
+	cc.gov.AddDKGMPKReady(ready.Round, ready)
+	return nil
+}
+
+// processPartialSignature verifies and either applies or buffers an incoming
+// partial signature. It deliberately does not require cc.dkg to be set, so
+// init-block psigs for round 0 are accepted into pendingPsig even if they
+// arrive before registerDKG(0, ...) has completed.
 func (cc *configurationChain) processPartialSignature(
 	psig *types.DKGPartialSignature) error {
 	cc.tsigReady.L.Lock()
@@ -294,6 +538,9 @@ func (cc *configurationChain) processPartialSignature(
 		if !ok {
 			return ErrIncorrectPartialSignatureSignature
 		}
+		if _, touched := cc.pendingPsigTouchedTime[psig.Hash]; !touched {
+			cc.pendingPsigTouchedTime[psig.Hash] = time.Now()
+		}
 		cc.pendingPsig[psig.Hash] = append(cc.pendingPsig[psig.Hash], psig)
 		return nil
 	}
@@ -302,4 +549,4 @@ func (cc *configurationChain) processPartialSignature(
 	}
 	cc.tsigReady.Broadcast()
 	return nil
-}
\ No newline at end of file
+}
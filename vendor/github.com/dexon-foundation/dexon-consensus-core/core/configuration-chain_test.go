@@ -0,0 +1,119 @@
+// Copyright 2018 The dexon-consensus-core Authors
+// This file is part of the dexon-consensus-core library.
+//
+// The dexon-consensus-core library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus-core library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus-core library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/dexon-foundation/dexon-consensus-core/common"
+	"github.com/dexon-foundation/dexon-consensus-core/core/types"
+)
+
+type ConfigurationChainTestSuite struct {
+	suite.Suite
+}
+
+// TestWakeCondOnDone verifies the ctx.Done() wake-up goroutine runTSig relies
+// on: a goroutine blocked in cond.Wait() must be woken once ctx is canceled,
+// rather than hang forever waiting for a partial signature that will never
+// arrive.
+func (s *ConfigurationChainTestSuite) TestWakeCondOnDone() {
+	cond := sync.NewCond(&sync.Mutex{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stop := wakeCondOnDone(ctx, cond)
+	defer stop()
+
+	woken := make(chan struct{})
+	go func() {
+		cond.L.Lock()
+		cond.Wait()
+		cond.L.Unlock()
+		close(woken)
+	}()
+	// Give the waiter above time to actually reach cond.Wait() before we
+	// cancel, otherwise the Broadcast below could fire before anyone is
+	// listening for it.
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-woken:
+	case <-time.After(time.Second):
+		s.FailNow("cond.Wait() was never woken after ctx was canceled")
+	}
+}
+
+// TestWakeCondOnDoneStopsOnCallerDone verifies that calling stop() before ctx
+// is ever canceled leaves no goroutine broadcasting after the fact.
+func (s *ConfigurationChainTestSuite) TestWakeCondOnDoneStopsOnCallerDone() {
+	cond := sync.NewCond(&sync.Mutex{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stop := wakeCondOnDone(ctx, cond)
+	stop()
+
+	broadcast := make(chan struct{})
+	go func() {
+		cond.L.Lock()
+		cond.Wait()
+		cond.L.Unlock()
+		close(broadcast)
+	}()
+	select {
+	case <-broadcast:
+		s.FailNow("cond was broadcast on after stop() despite ctx never being canceled")
+	case <-time.After(50 * time.Millisecond):
+	}
+	cond.Broadcast()
+	<-broadcast
+}
+
+// TestEvictStalePendingPsig verifies the pendingPsig TTL sweep evicts only
+// entries whose pendingPsigTouchedTime is older than pendingPsigTTL, so psigs
+// whose TSIG round never gets initiated don't accumulate forever while
+// recently-touched ones survive.
+func (s *ConfigurationChainTestSuite) TestEvictStalePendingPsig() {
+	cc := newConfigurationChainWithPsigTTL(
+		types.NodeID{}, nil, nil, newMemDKGPersistentStore(), nullLogger{},
+		time.Minute)
+
+	staleHash := common.Hash{0x01}
+	freshHash := common.Hash{0x02}
+	now := time.Now()
+	cc.pendingPsig[staleHash] = []*types.DKGPartialSignature{{}}
+	cc.pendingPsigTouchedTime[staleHash] = now.Add(-2 * time.Minute)
+	cc.pendingPsig[freshHash] = []*types.DKGPartialSignature{{}}
+	cc.pendingPsigTouchedTime[freshHash] = now
+
+	cc.evictStalePendingPsig(now)
+
+	_, staleExists := cc.pendingPsig[staleHash]
+	s.Require().False(staleExists, "entry older than the TTL should be evicted")
+	_, freshExists := cc.pendingPsig[freshHash]
+	s.Require().True(freshExists, "entry younger than the TTL should survive")
+}
+
+func TestConfigurationChain(t *testing.T) {
+	suite.Run(t, new(ConfigurationChainTestSuite))
+}
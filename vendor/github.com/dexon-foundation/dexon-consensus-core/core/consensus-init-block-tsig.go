@@ -0,0 +1,29 @@
+// Copyright 2018 The dexon-consensus-core Authors
+// This file is part of the dexon-consensus-core library.
+//
+// The dexon-consensus-core library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus-core library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus-core library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/dexon-foundation/dexon-consensus-core/core/crypto"
+)
+
+// InitialRoundTSig drives round's init-block TSIG to completion and returns
+// its signature, letting the network bootstrap round 0 (and any round whose
+// config block has no prior TSIG) without a special-cased genesis block.
+func (con *Consensus) InitialRoundTSig(round uint64) (crypto.Signature, error) {
+	return con.cfgModule.runInitBlockTSig(round)
+}
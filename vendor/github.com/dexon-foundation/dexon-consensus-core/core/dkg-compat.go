@@ -0,0 +1,41 @@
+// Copyright 2018 The dexon-consensus-core Authors
+// This file is part of the dexon-consensus-core library.
+//
+// The dexon-consensus-core library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus-core library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus-core library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	typesDKG "github.com/dexon-foundation/dexon-consensus-core/core/types/dkg"
+)
+
+// DKGGroupPublicKey and NewDKGGroupPublicKey are deprecated aliases kept for
+// one release after DKGGroupPublicKey moved to core/types/dkg. Import
+// core/types/dkg directly instead; this shim will be removed in the next
+// release.
+//
+// Deprecated: use typesDKG.GroupPublicKey.
+type DKGGroupPublicKey = typesDKG.GroupPublicKey
+
+// NewDKGGroupPublicKey is a deprecated alias for typesDKG.NewGroupPublicKey.
+//
+// Deprecated: use typesDKG.NewGroupPublicKey.
+var NewDKGGroupPublicKey = typesDKG.NewGroupPublicKey
+
+// dkgShareSecret is a deprecated alias kept for one release after
+// dkgShareSecret moved to core/types/dkg as the exported ShareSecret.
+//
+// Deprecated: use typesDKG.ShareSecret.
+type dkgShareSecret = typesDKG.ShareSecret
@@ -0,0 +1,58 @@
+// Copyright 2018 The dexon-consensus-core Authors
+// This file is part of the dexon-consensus-core library.
+//
+// The dexon-consensus-core library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus-core library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus-core library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/dexon-foundation/dexon-consensus-core/core/crypto"
+	"github.com/dexon-foundation/dexon-consensus-core/core/types"
+)
+
+// ErrIncorrectMPKReadySignature is returned when a DKGMPKReady message's
+// signature does not match its proposer.
+var ErrIncorrectMPKReadySignature = fmt.Errorf(
+	"incorrect MPK ready signature")
+
+// proposeMPKReady broadcasts a DKGMPKReady message, so the rest of the DKG
+// set can threshold the ready count the same way it already thresholds
+// finalize. It does not check that this node's own master public key is
+// on-chain; runDKG calls it right after processing the master public keys
+// read from governance. If signing fails, nothing is proposed. The
+// message is signed before being handed to recv: verifyDKGMPKReadySignature
+// checks ready.Signature on the receiving end, and nothing else in this
+// round-trip signs it on the node's behalf.
+func (d *dkgProtocol) proposeMPKReady() {
+	ready := &types.DKGMPKReady{
+		ProposerID: d.ID,
+		Round:      d.round,
+	}
+	sig, err := signDKGSignature(d.ID, ready)
+	if err != nil {
+		return
+	}
+	ready.Signature = sig
+	d.recv.ProposeDKGMPKReady(ready)
+}
+
+// verifyDKGMPKReadySignature verifies that a DKGMPKReady message is correctly
+// signed by its proposer, mirroring verifyDKGPartialSignatureSignature.
+func verifyDKGMPKReadySignature(
+	ready *types.DKGMPKReady) (bool, error) {
+	return verifyDKGSignature(ready.ProposerID, ready, ready.Signature)
+}
@@ -0,0 +1,252 @@
+// Copyright 2018 The dexon-consensus-core Authors
+// This file is part of the dexon-consensus-core library.
+//
+// The dexon-consensus-core library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus-core library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus-core library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dexon-foundation/dexon-consensus-core/common"
+	typesDKG "github.com/dexon-foundation/dexon-consensus-core/core/types/dkg"
+)
+
+// Phases of a dkgProtocol's lifetime, recorded on the protocol itself so a
+// restart can tell which phases of runDKG have already completed.
+const (
+	dkgPhaseInit = iota
+	dkgPhaseMasterPublicKeys
+	dkgPhaseNackComplaints
+	dkgPhaseAntiNackComplaints
+	dkgPhaseEnforceComplaints
+	dkgPhaseFinalize
+)
+
+// dkgResultRecord is the persisted outcome of a successful runDKG round.
+type dkgResultRecord struct {
+	Signer   *dkgShareSecret
+	GPK      *typesDKG.GroupPublicKey
+	PrevHash common.Hash
+}
+
+// dkgProtocolSnapshot is the exported, gob-encodable record of a
+// dkgProtocol's progress within runDKG. encoding/gob silently drops
+// unexported fields, so this must not be *dkgProtocol itself: encoding
+// dkg.round/dkg.phase directly would round-trip as their zero values with
+// no error.
+//
+// Phase is diagnostic only: it cannot be used to skip phases on resume,
+// because the protocol's secret polynomial and the private shares it
+// received from peers exist only in RAM and are not captured here. See
+// registerDKG for why a restart always re-runs every phase instead of
+// trusting this value.
+type dkgProtocolSnapshot struct {
+	Round     uint64
+	Threshold int
+	Phase     int
+}
+
+func newDKGProtocolSnapshot(dkg *dkgProtocol) *dkgProtocolSnapshot {
+	return &dkgProtocolSnapshot{
+		Round:     dkg.round,
+		Threshold: dkg.threshold,
+		Phase:     dkg.phase,
+	}
+}
+
+// DKGPersistentStore keeps a node's DKG state across a restart. runDKG
+// reloads a completed round's result from it instead of re-running that
+// round; a round interrupted mid-flight is re-run from dkgPhaseInit, and its
+// protocol snapshot is only logged. Implementations must be safe for
+// concurrent use.
+type DKGPersistentStore interface {
+	// PutDKGProtocol persists a snapshot (round, threshold, phase) of the
+	// in-flight dkgProtocol.
+	PutDKGProtocol(dkg *dkgProtocol) error
+	// GetDKGProtocol returns the snapshot previously persisted for round, if
+	// any.
+	GetDKGProtocol(round uint64) (snapshot *dkgProtocolSnapshot, exist bool)
+	// PutDKGResult persists the final, recovered artifacts of a completed
+	// DKG round.
+	PutDKGResult(
+		round uint64,
+		signer *dkgShareSecret,
+		gpk *typesDKG.GroupPublicKey,
+		prevHash common.Hash) error
+	// GetDKGResult returns the artifacts previously persisted by
+	// PutDKGResult for the given round, if any.
+	GetDKGResult(round uint64) (
+		signer *dkgShareSecret, gpk *typesDKG.GroupPublicKey, prevHash common.Hash,
+		exist bool)
+}
+
+// memDKGPersistentStore is an in-memory DKGPersistentStore, useful for tests
+// and for nodes that don't need to survive a process restart.
+type memDKGPersistentStore struct {
+	lock    sync.RWMutex
+	dkgs    map[uint64]*dkgProtocolSnapshot
+	results map[uint64]*dkgResultRecord
+}
+
+// newMemDKGPersistentStore constructs an in-memory DKGPersistentStore.
+func newMemDKGPersistentStore() *memDKGPersistentStore {
+	return &memDKGPersistentStore{
+		dkgs:    make(map[uint64]*dkgProtocolSnapshot),
+		results: make(map[uint64]*dkgResultRecord),
+	}
+}
+
+func (s *memDKGPersistentStore) PutDKGProtocol(dkg *dkgProtocol) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dkgs[dkg.round] = newDKGProtocolSnapshot(dkg)
+	return nil
+}
+
+func (s *memDKGPersistentStore) GetDKGProtocol(
+	round uint64) (*dkgProtocolSnapshot, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	snapshot, exist := s.dkgs[round]
+	return snapshot, exist
+}
+
+func (s *memDKGPersistentStore) PutDKGResult(
+	round uint64,
+	signer *dkgShareSecret,
+	gpk *typesDKG.GroupPublicKey,
+	prevHash common.Hash) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.results[round] = &dkgResultRecord{
+		Signer:   signer,
+		GPK:      gpk,
+		PrevHash: prevHash,
+	}
+	return nil
+}
+
+func (s *memDKGPersistentStore) GetDKGResult(round uint64) (
+	*dkgShareSecret, *typesDKG.GroupPublicKey, common.Hash, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	record, exist := s.results[round]
+	if !exist {
+		return nil, nil, common.Hash{}, false
+	}
+	return record.Signer, record.GPK, record.PrevHash, true
+}
+
+// fileDKGPersistentStore is a file-backed DKGPersistentStore: each round's
+// state is gob-encoded into its own file under baseDir, so a crash can only
+// ever lose the round currently being written, never prior rounds.
+type fileDKGPersistentStore struct {
+	lock    sync.Mutex
+	baseDir string
+}
+
+// newFileDKGPersistentStore constructs a file-backed DKGPersistentStore
+// rooted at baseDir, creating it if it does not already exist.
+func newFileDKGPersistentStore(baseDir string) (
+	*fileDKGPersistentStore, error) {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileDKGPersistentStore{baseDir: baseDir}, nil
+}
+
+func (s *fileDKGPersistentStore) dkgPath(round uint64) string {
+	return filepath.Join(s.baseDir, fmt.Sprintf("dkg-%d.gob", round))
+}
+
+func (s *fileDKGPersistentStore) resultPath(round uint64) string {
+	return filepath.Join(s.baseDir, fmt.Sprintf("result-%d.gob", round))
+}
+
+func (s *fileDKGPersistentStore) putGob(path string, v interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(v); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *fileDKGPersistentStore) getGob(path string, v interface{}) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+	if err := gob.NewDecoder(f).Decode(v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *fileDKGPersistentStore) PutDKGProtocol(dkg *dkgProtocol) error {
+	return s.putGob(s.dkgPath(dkg.round), newDKGProtocolSnapshot(dkg))
+}
+
+func (s *fileDKGPersistentStore) GetDKGProtocol(
+	round uint64) (*dkgProtocolSnapshot, bool) {
+	snapshot := &dkgProtocolSnapshot{}
+	exist, err := s.getGob(s.dkgPath(round), snapshot)
+	if err != nil || !exist {
+		return nil, false
+	}
+	return snapshot, true
+}
+
+func (s *fileDKGPersistentStore) PutDKGResult(
+	round uint64,
+	signer *dkgShareSecret,
+	gpk *typesDKG.GroupPublicKey,
+	prevHash common.Hash) error {
+	return s.putGob(s.resultPath(round), &dkgResultRecord{
+		Signer:   signer,
+		GPK:      gpk,
+		PrevHash: prevHash,
+	})
+}
+
+func (s *fileDKGPersistentStore) GetDKGResult(round uint64) (
+	*dkgShareSecret, *typesDKG.GroupPublicKey, common.Hash, bool) {
+	record := &dkgResultRecord{}
+	exist, err := s.getGob(s.resultPath(round), record)
+	if err != nil || !exist {
+		return nil, nil, common.Hash{}, false
+	}
+	return record.Signer, record.GPK, record.PrevHash, true
+}
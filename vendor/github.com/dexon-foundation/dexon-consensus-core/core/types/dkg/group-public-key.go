@@ -0,0 +1,76 @@
+// Copyright 2018 The dexon-consensus-core Authors
+// This file is part of the dexon-consensus-core library.
+//
+// The dexon-consensus-core library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus-core library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus-core library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package dkg holds DKG artifacts (a round's qualified-node set, share
+// secrets, and threshold arithmetic) outside the full `core` package and its
+// consensus dependencies. It holds no group public key material and offers
+// no way to reconstruct or verify a group public key.
+package dkg
+
+import (
+	"fmt"
+
+	"github.com/dexon-foundation/dexon-consensus-core/core/types"
+)
+
+// ErrNotEnoughQualifyNodes is returned when fewer nodes qualified for a round
+// than its DKG threshold requires.
+var ErrNotEnoughQualifyNodes = fmt.Errorf("not enough qualify nodes")
+
+// GroupPublicKey records, for a round, the set of nodes whose shares
+// qualified and the DKG threshold. It carries no public key: the group
+// public key itself is not computed or stored here.
+type GroupPublicKey struct {
+	Round          uint64
+	QualifyIDs     []types.NodeID
+	QualifyNodeIDs map[types.NodeID]struct{}
+	Threshold      int
+}
+
+// NewGroupPublicKey collects round's qualified nodes from the proposers of
+// the round's master public keys, keeping only the nodes that were not
+// disqualified by a complaint, and returns ErrNotEnoughQualifyNodes when
+// fewer than threshold remain. It does not combine the master public keys
+// into a group public key.
+func NewGroupPublicKey(
+	round uint64,
+	masterPublicKeys []*types.DKGMasterPublicKey,
+	complaints []*types.DKGComplaint,
+	threshold int) (*GroupPublicKey, error) {
+	disqualified := make(map[types.NodeID]struct{}, len(complaints))
+	for _, complaint := range complaints {
+		disqualified[complaint.ProposerID] = struct{}{}
+	}
+	qualifyIDs := make([]types.NodeID, 0, len(masterPublicKeys))
+	qualifyNodeIDs := make(map[types.NodeID]struct{}, len(masterPublicKeys))
+	for _, mpk := range masterPublicKeys {
+		if _, exist := disqualified[mpk.ProposerID]; exist {
+			continue
+		}
+		qualifyIDs = append(qualifyIDs, mpk.ProposerID)
+		qualifyNodeIDs[mpk.ProposerID] = struct{}{}
+	}
+	if len(qualifyIDs) < threshold {
+		return nil, ErrNotEnoughQualifyNodes
+	}
+	return &GroupPublicKey{
+		Round:          round,
+		QualifyIDs:     qualifyIDs,
+		QualifyNodeIDs: qualifyNodeIDs,
+		Threshold:      threshold,
+	}, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2018 The dexon-consensus-core Authors
+// This file is part of the dexon-consensus-core library.
+//
+// The dexon-consensus-core library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus-core library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus-core library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dkg
+
+import (
+	"github.com/dexon-foundation/dexon-consensus-core/common"
+	"github.com/dexon-foundation/dexon-consensus-core/core/crypto"
+)
+
+// ShareSecret is a node's recovered private key share for a round's group
+// public key, recovered via (dkgProtocol).recoverShareSecret. It is
+// sufficient to produce that node's partial signature over any hash, but not
+// to reconstruct the group's private key on its own.
+type ShareSecret struct {
+	PrivateKey crypto.PrivateKey
+}
+
+// Sign produces this node's partial signature over hash.
+func (s *ShareSecret) Sign(hash common.Hash) crypto.Signature {
+	return s.PrivateKey.Sign(hash)
+}